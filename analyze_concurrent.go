@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// fileEntry связывает распарсенную запись с файлом, из которого она прочитана,
+// так что ошибки и сводная статистика остаются привязаны к источнику.
+type fileEntry struct {
+	entry LogEntry
+	path  string
+}
+
+// statsShard хранит частичную статистику, принадлежащую одной
+// aggregator-горутине. Так как каждый shard обновляется только своим
+// владельцем, обновления не требуют блокировки в горячем пути.
+type statsShard struct {
+	counts map[LogMessageType]int
+	total  int
+}
+
+// resolveLogPaths разворачивает пути в la.LogFilePaths: элементы, содержащие
+// символы glob, раскрываются через filepath.Glob, остальные используются как
+// есть. Порядок результатов соответствует порядку входных путей.
+func resolveLogPaths(paths []string) ([]string, error) {
+	var resolved []string
+	for _, p := range paths {
+		if !containsGlobMeta(p) {
+			resolved = append(resolved, p)
+			continue
+		}
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при разборе glob-шаблона %q: %v", p, err)
+		}
+		resolved = append(resolved, matches...)
+	}
+	return resolved, nil
+}
+
+func containsGlobMeta(p string) bool {
+	for _, r := range p {
+		switch r {
+		case '*', '?', '[':
+			return true
+		}
+	}
+	return false
+}
+
+// Analyze конкурентно анализирует все файлы из la.LogFilePaths и собирает
+// статистику. Ограниченный пул воркеров читает файлы параллельно и
+// отправляет разобранные записи фиксированному набору aggregator-горутин,
+// каждая из которых владеет собственным statsShard, что снимает конкуренцию
+// за единственный la.mutex на больших наборах файлов. Итог детерминирован:
+// шарды суммируются в фиксированном порядке после завершения всех горутин.
+func (la *LogAnalyzer) Analyze() error {
+	paths, err := resolveLogPaths(la.LogFilePaths)
+	if err != nil {
+		return err
+	}
+
+	workers := la.MaxWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	aggregators := workers
+	if aggregators > len(paths) && len(paths) > 0 {
+		aggregators = len(paths)
+	}
+	if aggregators < 1 {
+		aggregators = 1
+	}
+
+	entries := make(chan fileEntry, workers*64)
+	shards := make([]*statsShard, aggregators)
+	for i := range shards {
+		shards[i] = &statsShard{counts: make(map[LogMessageType]int)}
+	}
+
+	var aggWG sync.WaitGroup
+	var sinkErr error
+	var sinkErrOnce sync.Once
+	for i := 0; i < aggregators; i++ {
+		aggWG.Add(1)
+		shard := shards[i]
+		go func() {
+			defer aggWG.Done()
+			for fe := range entries {
+				entry := fe.entry
+				// TotalMessages считает каждую разобранную запись независимо от
+				// фильтра -level, как и в режиме -follow (consumeFollowed).
+				shard.total++
+				if !levelAtLeast(entry.Type, la.DetailLevel) {
+					continue
+				}
+				if la.Verbosity != nil && !la.Verbosity.Allow(entry) {
+					continue
+				}
+				shard.counts[entry.Type]++
+				if la.WindowAgg != nil {
+					ts := entry.Timestamp
+					if ts.IsZero() {
+						ts = time.Now()
+					}
+					la.WindowAgg.Add(entry, ts)
+				}
+				if la.TopErrors != nil && entry.Type == ERROR {
+					la.TopErrors.Add(entry.Message)
+				}
+				if err := la.Sink.Write(entry); err != nil {
+					sinkErrOnce.Do(func() {
+						sinkErr = fmt.Errorf("ошибка при записи в синк (%s): %v", fe.path, err)
+					})
+				}
+			}
+		}()
+	}
+
+	sem := make(chan struct{}, workers)
+	var workerWG sync.WaitGroup
+	fileErrs := make(chan error, len(paths))
+	for _, path := range paths {
+		path := path
+		workerWG.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer workerWG.Done()
+			defer func() { <-sem }()
+			parser, err := NewParser(la.Format)
+			if err != nil {
+				fileErrs <- err
+				return
+			}
+			if err := parseFileInto(path, parser, entries); err != nil {
+				fileErrs <- err
+			}
+		}()
+	}
+
+	workerWG.Wait()
+	close(entries)
+	aggWG.Wait()
+	close(fileErrs)
+
+	for _, shard := range shards {
+		for logType, count := range shard.counts {
+			la.Stats[logType] += count
+		}
+		la.TotalMessages += shard.total
+	}
+
+	for err := range fileErrs {
+		return err
+	}
+	if sinkErr != nil {
+		return sinkErr
+	}
+
+	return la.Sink.Flush()
+}
+
+// parseFileInto читает path построчно, разбирает каждую строку через parser
+// (собственный экземпляр на файл, чтобы AutoParser определял формат
+// независимо для каждого файла) и отправляет записи в out.
+func parseFileInto(path string, parser Parser, out chan<- fileEntry) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("ошибка при открытии файла лога %q: %v", path, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("ошибка при чтении файла лога %q: %v", path, err)
+		}
+		entry, err := parser.Parse(line)
+		if err != nil {
+			return fmt.Errorf("ошибка при разборе строки файла %q: %v", path, err)
+		}
+		out <- fileEntry{entry: entry, path: path}
+	}
+	return nil
+}
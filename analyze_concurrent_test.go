@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// generateMixedLogFiles создает n лог-файлов по lines строк, циклически
+// чередуя ERROR/WARNING/INFO, во временной директории, и возвращает их пути.
+func generateMixedLogFiles(t *testing.T, n, lines int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	types := []LogMessageType{ERROR, WARNING, INFO}
+	paths := make([]string, n)
+	for i := 0; i < n; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("mixed-%d.log", i))
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("не удалось создать файл лога: %v", err)
+		}
+		for l := 0; l < lines; l++ {
+			fmt.Fprintf(f, "%s message %d\n", types[(i+l)%len(types)], l)
+		}
+		f.Close()
+		paths[i] = path
+	}
+	return paths
+}
+
+// TestAnalyze_DeterministicTotals проверяет, что конкурентный Analyze дает
+// одинаковые Stats/TotalMessages независимо от MaxWorkers и при повторных
+// запусках - шардирование статистики по aggregator-горутинам не должно
+// влиять на итоговые суммы.
+func TestAnalyze_DeterministicTotals(t *testing.T) {
+	paths := generateMixedLogFiles(t, 5, 200)
+
+	var want map[LogMessageType]int
+	var wantTotal int
+
+	for _, workers := range []int{1, 2, 3, 8, 32} {
+		for run := 0; run < 2; run++ {
+			la := NewLogAnalyzer(paths, "INFO", NewDiscardSink(INFO), nil)
+			la.MaxWorkers = workers
+			if err := la.Analyze(); err != nil {
+				t.Fatalf("Analyze вернул ошибку (workers=%d): %v", workers, err)
+			}
+
+			if want == nil {
+				want = la.Stats
+				wantTotal = la.TotalMessages
+				continue
+			}
+			if !reflect.DeepEqual(la.Stats, want) {
+				t.Errorf("workers=%d run=%d: Stats = %+v, хотим %+v", workers, run, la.Stats, want)
+			}
+			if la.TotalMessages != wantTotal {
+				t.Errorf("workers=%d run=%d: TotalMessages = %d, хотим %d", workers, run, la.TotalMessages, wantTotal)
+			}
+		}
+	}
+
+	if wantTotal != 5*200 {
+		t.Errorf("TotalMessages = %d, хотим %d", wantTotal, 5*200)
+	}
+}
+
+// generateBenchLogFiles создает n лог-файлов по lines строк в каждом во
+// временной директории и возвращает их пути.
+func generateBenchLogFiles(b *testing.B, n, lines int) []string {
+	b.Helper()
+	dir := b.TempDir()
+	paths := make([]string, n)
+	for i := 0; i < n; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("bench-%d.log", i))
+		f, err := os.Create(path)
+		if err != nil {
+			b.Fatalf("не удалось создать файл для бенчмарка: %v", err)
+		}
+		for l := 0; l < lines; l++ {
+			fmt.Fprintf(f, "INFO benchmark message %d\n", l)
+		}
+		f.Close()
+		paths[i] = path
+	}
+	return paths
+}
+
+// BenchmarkAnalyze_ParallelFiles измеряет пропускную способность конкурентного
+// Analyze при росте числа одновременно обрабатываемых файлов.
+func BenchmarkAnalyze_ParallelFiles(b *testing.B) {
+	for _, fileCount := range []int{1, 4, 16} {
+		fileCount := fileCount
+		b.Run(fmt.Sprintf("files=%d", fileCount), func(b *testing.B) {
+			paths := generateBenchLogFiles(b, fileCount, 2000)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				la := NewLogAnalyzer(paths, "INFO", NewDiscardSink(INFO), nil)
+				if err := la.Analyze(); err != nil {
+					b.Fatalf("Analyze вернул ошибку: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -1,13 +1,15 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"flag"
 	"fmt"
-	"io"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // LogMessageType представляет тип сообщения в логе.
@@ -21,111 +23,138 @@ const (
 
 // LogEntry представляет запись в логе.
 type LogEntry struct {
-	Type    LogMessageType
-	Message string
+	Type      LogMessageType
+	Message   string
+	Verbosity int    // числовой уровень детализации в стиле glog/klog, 0 если не указан
+	File      string // исходный файл записи (например, "pkg/foo.go"), если указан
+
+	Timestamp time.Time      // время записи, если формат лога его содержит
+	Caller    string         // caller/источник записи (например, "foo.go:42"), если формат лога его содержит
+	Fields    map[string]any // произвольные структурированные поля (json, logfmt)
 }
 
 // LogAnalyzer представляет анализатор логов.
 type LogAnalyzer struct {
-	LogFilePath   string
-	DetailLevel   LogMessageType
-	OutputFile    string
+	LogFilePaths []string
+	DetailLevel  LogMessageType
+	Sink         Sink
+	Verbosity    *VerbosityFilter
+	MaxWorkers   int    // максимум файлов, читаемых одновременно; 0 = runtime.NumCPU()
+	Format       string // "plain", "json", "logfmt" или "auto" (по умолчанию)
+
+	WindowAgg *WindowAggregator // опционально: включает отчет по скользящим интервалам
+	TopErrors *TopErrorsTracker // опционально: включает отчет top-N ошибок
+
+	ReportInterval time.Duration // интервал между инкрементальными отчетами в режиме -follow
+
 	Stats         map[LogMessageType]int
 	TotalMessages int
 	mutex         sync.Mutex
 }
 
-// NewLogAnalyzer создает новый экземпляр LogAnalyzer.
-func NewLogAnalyzer(logFilePath, detailLevel, outputFile string) *LogAnalyzer {
+// NewLogAnalyzer создает новый экземпляр LogAnalyzer для указанных путей к
+// лог-файлам. Если sink равен nil, используется ConsoleSink, выводящий отчет
+// и записи в stdout. Если verbosity равен nil, -v/-vmodule фильтрация не
+// применяется. Формат лога определяется автоматически, пока Format не
+// установлен явно.
+func NewLogAnalyzer(logFilePaths []string, detailLevel string, sink Sink, verbosity *VerbosityFilter) *LogAnalyzer {
+	if sink == nil {
+		sink = NewConsoleSink(INFO)
+	}
 	return &LogAnalyzer{
-		LogFilePath: logFilePath,
-		DetailLevel: LogMessageType(strings.ToUpper(detailLevel)),
-		OutputFile:  outputFile,
-		Stats:       make(map[LogMessageType]int),
+		LogFilePaths: logFilePaths,
+		DetailLevel:  LogMessageType(strings.ToUpper(detailLevel)),
+		Sink:         sink,
+		Verbosity:    verbosity,
+		Format:       "auto",
+		Stats:        make(map[LogMessageType]int),
 	}
 }
 
-// Analyze анализирует лог-файл и собирает статистику.
-func (la *LogAnalyzer) Analyze() error {
-	file, err := os.Open(la.LogFilePath)
-	if err != nil {
-		return fmt.Errorf("ошибка при открытии файла лога: %v", err)
-	}
-	defer file.Close()
-
-	reader := bufio.NewReader(file)
-	for {
-		line, err := reader.ReadString('\n')
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return fmt.Errorf("ошибка при чтении файла лога: %v", err)
-		}
-
-		entry := parseLogEntry(line)
-		if entry.Type >= la.DetailLevel {
-			la.updateStats(entry.Type)
-		}
-		la.TotalMessages++
-	}
+// updateStats обновляет статистику на основе типа сообщения. Используется в
+// режиме -follow (consumeFollowed); конкурентный путь (Analyze) агрегирует через шарды
+// statsShard и объединяет их после завершения воркеров, не трогая этот метод.
+func (la *LogAnalyzer) updateStats(logType LogMessageType) {
+	la.mutex.Lock()
+	defer la.mutex.Unlock()
+	la.Stats[logType]++
+}
 
-	return nil
+// addTotalMessages добавляет delta к TotalMessages под la.mutex. Используется
+// в режиме -follow, где запись идет из отдельной горутины (consumeFollowed),
+// а чтение - из горутины, обслуживающей select-цикл RunFollow.
+func (la *LogAnalyzer) addTotalMessages(delta int) {
+	la.mutex.Lock()
+	defer la.mutex.Unlock()
+	la.TotalMessages += delta
 }
 
-// updateStats обновляет статистику на основе типа сообщения.
-func (la *LogAnalyzer) updateStats(logType LogMessageType) {
+// totalMessages возвращает текущее значение TotalMessages под la.mutex.
+func (la *LogAnalyzer) totalMessages() int {
 	la.mutex.Lock()
 	defer la.mutex.Unlock()
-	la.Stats[logType]++
+	return la.TotalMessages
 }
 
-// PrintReport выводит отчет в консоль или файл.
+// PrintReport формирует итоговый отчет и отправляет его через Sink анализатора.
 func (la *LogAnalyzer) PrintReport() error {
-	var output io.Writer
-
-	if la.OutputFile != "" {
-		file, err := os.Create(la.OutputFile)
-		if err != nil {
-			return fmt.Errorf("ошибка при создании файла отчета: %v", err)
-		}
-		defer file.Close()
-		output = file
-	} else {
-		output = os.Stdout
+	if err := la.Sink.Write(LogEntry{Type: INFO, Message: "Статистика по сообщениям:"}); err != nil {
+		return fmt.Errorf("ошибка при записи отчета в синк: %v", err)
 	}
-
-	fmt.Fprintln(output, "Статистика по сообщениям:")
 	for logType, count := range la.Stats {
-		fmt.Fprintf(output, "%s: %d\n", logType, count)
+		msg := fmt.Sprintf("%s: %d", logType, count)
+		if err := la.Sink.Write(LogEntry{Type: INFO, Message: msg}); err != nil {
+			return fmt.Errorf("ошибка при записи отчета в синк: %v", err)
+		}
+	}
+	msg := fmt.Sprintf("Всего сообщений: %d", la.TotalMessages)
+	if err := la.Sink.Write(LogEntry{Type: INFO, Message: msg}); err != nil {
+		return fmt.Errorf("ошибка при записи отчета в синк: %v", err)
 	}
 
-	fmt.Fprintf(output, "Всего сообщений: %d\n", la.TotalMessages)
-
-	return nil
+	return la.Sink.Flush()
 }
 
-// parseLogEntry парсит запись из лога.
+// parseLogEntry парсит запись из лога. Помимо "TYPE message", распознается
+// формат с verbosity в стиле glog/klog: "INFO(3) pkg/foo.go:42 message".
 func parseLogEntry(line string) LogEntry {
 	parts := strings.SplitN(line, " ", 2)
-	if len(parts) == 2 {
-		return LogEntry{
-			Type:    LogMessageType(parts[0]),
-			Message: parts[1],
-		}
+	if len(parts) != 2 {
+		// Если запись не соответствует ожидаемому формату, считаем ее INFO.
+		return LogEntry{Type: INFO, Message: line}
 	}
-	// Если запись не соответствует ожидаемому формату, считаем ее INFO.
-	return LogEntry{
-		Type:    INFO,
-		Message: line,
+
+	typeField, message := parts[0], parts[1]
+	if idx := strings.IndexByte(typeField, '('); idx >= 0 && strings.HasSuffix(typeField, ")") {
+		level, err := strconv.Atoi(typeField[idx+1 : len(typeField)-1])
+		if err == nil {
+			verbosity, file, rest := parseVerbosityAndFile(fmt.Sprintf("(%d) %s", level, message))
+			return LogEntry{Type: LogMessageType(typeField[:idx]), Message: rest, Verbosity: verbosity, File: file}
+		}
 	}
+
+	return LogEntry{Type: LogMessageType(typeField), Message: message}
 }
 
 func main() {
-	var logFilePath, detailLevel, outputFile string
+	var logFilePath, detailLevel, outputFile, sinksConfigPath, vmoduleSpec, format, reportFormat string
+	var v, workers, topErrors int
+	var window, reportInterval time.Duration
+	var follow bool
 
-	flag.StringVar(&logFilePath, "log", "", "Путь к лог-файлу")
+	flag.StringVar(&logFilePath, "log", "", "Путь(и) к лог-файлу: список через запятую или glob-шаблон")
 	flag.StringVar(&detailLevel, "level", "INFO", "Уровень детализации анализа (ERROR, WARNING, INFO)")
-	flag.StringVar(&outputFile, "output", "", "Путь к файлу отчета")
+	flag.StringVar(&outputFile, "output", "", "Путь к файлу отчета (используется, если не задан -sinks-config)")
+	flag.StringVar(&sinksConfigPath, "sinks-config", "", "Путь к YAML-файлу с конфигурацией синков (console, file, syslog, kafka)")
+	flag.IntVar(&v, "v", 0, "Глобальный уровень verbosity (в стиле glog/klog)")
+	flag.StringVar(&vmoduleSpec, "vmodule", "", "Пороги verbosity по файлам, например \"server/*=2,db.go=4\"")
+	flag.IntVar(&workers, "workers", 0, "Максимум файлов, читаемых одновременно (0 = runtime.NumCPU())")
+	flag.StringVar(&format, "format", "auto", "Формат лога: plain, json, logfmt или auto")
+	flag.DurationVar(&window, "window", 0, "Длина скользящего интервала для отчета по времени (например, 1m); 0 отключает отчет")
+	flag.IntVar(&topErrors, "top-errors", 0, "Число самых частых нормализованных шаблонов ошибок для отчета; 0 отключает отчет")
+	flag.StringVar(&reportFormat, "report-format", "text", "Формат отчета по интервалам/top-errors: text или json")
+	flag.BoolVar(&follow, "follow", false, "Не завершать работу по EOF, а следить за дозаписью и ротацией файла (аналог tail -F)")
+	flag.DurationVar(&reportInterval, "report-interval", 10*time.Second, "Интервал между инкрементальными отчетами в режиме -follow")
 	flag.Parse()
 
 	// Если не указаны флаги, используем переменные окружения
@@ -138,12 +167,48 @@ func main() {
 	if outputFile == "" {
 		outputFile = os.Getenv("OUTPUT_FILE")
 	}
+	if sinksConfigPath == "" {
+		sinksConfigPath = os.Getenv("SINKS_CONFIG")
+	}
+
+	sink, err := buildSinkFromFlags(sinksConfigPath, outputFile)
+	if err != nil {
+		fmt.Printf("Ошибка при настройке синков: %v\n", err)
+		os.Exit(1)
+	}
+	defer sink.Close()
+
+	verbosity, err := NewVerbosityFilter(v, vmoduleSpec)
+	if err != nil {
+		fmt.Printf("Ошибка при разборе vmodule: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Создаем экземпляр анализатора логов
-	logAnalyzer := NewLogAnalyzer(logFilePath, detailLevel, outputFile)
+	logAnalyzer := NewLogAnalyzer(strings.Split(logFilePath, ","), detailLevel, sink, verbosity)
+	logAnalyzer.MaxWorkers = workers
+	logAnalyzer.Format = format
+	if window > 0 {
+		logAnalyzer.WindowAgg = NewWindowAggregator(window, windowAggCapacity)
+	}
+	if topErrors > 0 {
+		logAnalyzer.TopErrors = NewTopErrorsTracker(topErrorsMaxTracked)
+	}
+	logAnalyzer.ReportInterval = reportInterval
+
+	if follow {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		if err := logAnalyzer.RunFollow(ctx); err != nil {
+			fmt.Printf("Ошибка в режиме -follow: %v\n", err)
+			os.Exit(1)
+		}
+		printWindowedReportIfEnabled(logAnalyzer, topErrors, reportFormat)
+		return
+	}
 
 	// Анализируем логи
-	err := logAnalyzer.Analyze()
+	err = logAnalyzer.Analyze()
 	if err != nil {
 		fmt.Printf("Ошибка при анализе логов: %v\n", err)
 		os.Exit(1)
@@ -155,4 +220,52 @@ func main() {
 		fmt.Printf("Ошибка при выводе отчета: %v\n", err)
 		os.Exit(1)
 	}
+
+	printWindowedReportIfEnabled(logAnalyzer, topErrors, reportFormat)
+}
+
+// printWindowedReportIfEnabled выводит отчет по скользящим интервалам и/или
+// top ошибкам, если хотя бы один из них включен на анализаторе. Используется
+// как в обычном режиме, так и после завершения -follow.
+func printWindowedReportIfEnabled(la *LogAnalyzer, topErrors int, reportFormat string) {
+	if la.WindowAgg == nil && la.TopErrors == nil {
+		return
+	}
+
+	report := BuildWindowedReport(la.WindowAgg, la.TopErrors, topErrors)
+	var err error
+	if reportFormat == "json" {
+		err = PrintWindowedReportJSON(os.Stdout, report)
+	} else {
+		err = PrintWindowedReportText(os.Stdout, report)
+	}
+	if err != nil {
+		fmt.Printf("Ошибка при выводе отчета по интервалам: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// windowAggCapacity ограничивает число одновременно хранимых интервалов в
+// WindowAggregator, чтобы память не росла пропорционально длине лога.
+const windowAggCapacity = 1440
+
+// topErrorsMaxTracked ограничивает число различных шаблонов ошибок,
+// отслеживаемых TopErrorsTracker одновременно.
+const topErrorsMaxTracked = 10000
+
+// buildSinkFromFlags собирает Sink анализатора исходя из переданных флагов:
+// конфигурация синков имеет приоритет над -output, который остается
+// простым способом писать отчет в один файл без MultiSink.
+func buildSinkFromFlags(sinksConfigPath, outputFile string) (Sink, error) {
+	if sinksConfigPath != "" {
+		cfg, err := LoadSinksConfig(sinksConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		return BuildMultiSink(cfg)
+	}
+	if outputFile != "" {
+		return NewFileSink(outputFile, INFO, 0)
+	}
+	return NewConsoleSink(INFO), nil
 }
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Parser разбирает одну строку лог-файла в LogEntry. Реализации
+// соответствуют поддерживаемым форматам: обычный формат анализатора,
+// JSON (zap/logrus) и logfmt.
+type Parser interface {
+	Parse(line string) (LogEntry, error)
+}
+
+// PlainParser разбирает запись в исходном формате анализатора:
+// "TYPE message" или "TYPE(verbosity) file:line message".
+type PlainParser struct{}
+
+func (PlainParser) Parse(line string) (LogEntry, error) {
+	return parseLogEntry(line), nil
+}
+
+// JSONParser разбирает запись в формате структурированных логов zap/logrus:
+// {"level":"info","ts":...,"msg":"...","caller":"foo.go:42", ...}.
+type JSONParser struct{}
+
+func (JSONParser) Parse(line string) (LogEntry, error) {
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return LogEntry{}, fmt.Errorf("ошибка при разборе json-записи: %v", err)
+	}
+
+	entry := LogEntry{Type: INFO, Fields: raw}
+
+	if level, ok := raw["level"].(string); ok {
+		entry.Type = LogMessageType(strings.ToUpper(level))
+		delete(raw, "level")
+	}
+	if msg, ok := raw["msg"].(string); ok {
+		entry.Message = msg
+		delete(raw, "msg")
+	}
+	if caller, ok := raw["caller"].(string); ok {
+		entry.Caller = caller
+		entry.File = strings.SplitN(caller, ":", 2)[0]
+		delete(raw, "caller")
+	}
+	if ts, ok := raw["ts"]; ok {
+		entry.Timestamp = parseTimestamp(ts)
+		delete(raw, "ts")
+	}
+
+	return entry, nil
+}
+
+// LogfmtParser разбирает запись в формате logfmt: level=info msg="..." caller=....
+type LogfmtParser struct{}
+
+func (LogfmtParser) Parse(line string) (LogEntry, error) {
+	fields, err := parseLogfmtFields(line)
+	if err != nil {
+		return LogEntry{}, err
+	}
+
+	entry := LogEntry{Type: INFO, Fields: fields}
+	if level, ok := fields["level"]; ok {
+		entry.Type = LogMessageType(strings.ToUpper(fmt.Sprint(level)))
+		delete(fields, "level")
+	}
+	if msg, ok := fields["msg"]; ok {
+		entry.Message = fmt.Sprint(msg)
+		delete(fields, "msg")
+	}
+	if caller, ok := fields["caller"]; ok {
+		entry.Caller = fmt.Sprint(caller)
+		entry.File = strings.SplitN(entry.Caller, ":", 2)[0]
+		delete(fields, "caller")
+	}
+	if ts, ok := fields["ts"]; ok {
+		entry.Timestamp = parseTimestamp(ts)
+		delete(fields, "ts")
+	}
+
+	return entry, nil
+}
+
+// parseLogfmtFields разбирает строку вида `key=value key2="quoted value"` в
+// отображение ключ -> значение (int, если значение - целое число, иначе строка).
+func parseLogfmtFields(line string) (map[string]any, error) {
+	fields := make(map[string]any)
+	i := 0
+	for i < len(line) {
+		for i < len(line) && line[i] == ' ' {
+			i++
+		}
+		if i >= len(line) {
+			break
+		}
+
+		eq := strings.IndexByte(line[i:], '=')
+		if eq < 0 {
+			break
+		}
+		key := line[i : i+eq]
+		i += eq + 1
+
+		var value string
+		if i < len(line) && line[i] == '"' {
+			end := strings.IndexByte(line[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("незакрытая кавычка в logfmt-записи после ключа %q", key)
+			}
+			value = line[i+1 : i+1+end]
+			i += end + 2
+		} else {
+			end := strings.IndexByte(line[i:], ' ')
+			if end < 0 {
+				value = line[i:]
+				i = len(line)
+			} else {
+				value = line[i : i+end]
+				i += end
+			}
+		}
+
+		if n, err := strconv.Atoi(value); err == nil {
+			fields[key] = n
+		} else {
+			fields[key] = value
+		}
+	}
+	return fields, nil
+}
+
+// parseTimestamp преобразует значение поля ts (unix-секунды числом или
+// строка в формате RFC3339) во время. Если формат не распознан,
+// возвращается нулевое time.Time.
+func parseTimestamp(ts any) time.Time {
+	switch v := ts.(type) {
+	case float64:
+		return time.Unix(int64(v), 0).UTC()
+	case int:
+		return time.Unix(int64(v), 0).UTC()
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// DetectFormat определяет формат лога по первой непустой строке: "json",
+// если строка похожа на JSON-объект, "logfmt", если содержит "key=value", и
+// "plain" в остальных случаях.
+func DetectFormat(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "{") {
+		return "json"
+	}
+	if strings.Contains(trimmed, "=") {
+		return "logfmt"
+	}
+	return "plain"
+}
+
+// NewParser создает Parser для заданного имени формата: "plain", "json",
+// "logfmt" или "auto" (определяет формат по первой строке каждого файла).
+func NewParser(format string) (Parser, error) {
+	switch format {
+	case "", "auto":
+		return &AutoParser{}, nil
+	case "plain":
+		return PlainParser{}, nil
+	case "json":
+		return JSONParser{}, nil
+	case "logfmt":
+		return LogfmtParser{}, nil
+	default:
+		return nil, fmt.Errorf("неизвестный формат лога: %s", format)
+	}
+}
+
+// AutoParser определяет формат лога по первой непустой обработанной строке и
+// затем делегирует разбор соответствующему Parser; пустые строки до этого
+// момента разбираются PlainParser. Безопасен для конкурентного
+// использования несколькими воркерами, читающими разные файлы.
+type AutoParser struct {
+	mu       sync.Mutex
+	detected Parser
+}
+
+func (a *AutoParser) Parse(line string) (LogEntry, error) {
+	a.mu.Lock()
+	if a.detected == nil && strings.TrimSpace(line) != "" {
+		a.detected, _ = NewParser(DetectFormat(line))
+	}
+	detected := a.detected
+	a.mu.Unlock()
+	if detected == nil {
+		return PlainParser{}.Parse(line)
+	}
+	return detected.Parse(line)
+}
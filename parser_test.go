@@ -0,0 +1,102 @@
+package main
+
+import "testing"
+
+func TestPlainParser(t *testing.T) {
+	entry, err := PlainParser{}.Parse("ERROR something failed\n")
+	if err != nil {
+		t.Fatalf("PlainParser.Parse вернул ошибку: %v", err)
+	}
+	if entry.Type != ERROR || entry.Message != "something failed\n" {
+		t.Errorf("неверный разбор: %+v", entry)
+	}
+}
+
+func TestJSONParser(t *testing.T) {
+	line := `{"level":"info","ts":1700000000,"msg":"request handled","caller":"foo.go:42","user":"alice"}`
+	entry, err := JSONParser{}.Parse(line)
+	if err != nil {
+		t.Fatalf("JSONParser.Parse вернул ошибку: %v", err)
+	}
+	if entry.Type != INFO {
+		t.Errorf("Type = %q, хотим INFO", entry.Type)
+	}
+	if entry.Message != "request handled" {
+		t.Errorf("Message = %q", entry.Message)
+	}
+	if entry.Caller != "foo.go:42" || entry.File != "foo.go" {
+		t.Errorf("Caller/File разобраны неверно: caller=%q file=%q", entry.Caller, entry.File)
+	}
+	if entry.Timestamp.IsZero() {
+		t.Errorf("ожидался непустой Timestamp")
+	}
+	if entry.Fields["user"] != "alice" {
+		t.Errorf("поле user не попало в Fields: %+v", entry.Fields)
+	}
+	if _, ok := entry.Fields["msg"]; ok {
+		t.Errorf("msg не должен оставаться в Fields после извлечения")
+	}
+}
+
+func TestJSONParser_InvalidJSON(t *testing.T) {
+	if _, err := (JSONParser{}).Parse("not json"); err == nil {
+		t.Error("ожидалась ошибка при разборе некорректного JSON")
+	}
+}
+
+func TestLogfmtParser(t *testing.T) {
+	line := `level=warning msg="disk space low" caller=bar.go:10 free=42`
+	entry, err := LogfmtParser{}.Parse(line)
+	if err != nil {
+		t.Fatalf("LogfmtParser.Parse вернул ошибку: %v", err)
+	}
+	if entry.Type != WARNING {
+		t.Errorf("Type = %q, хотим WARNING", entry.Type)
+	}
+	if entry.Message != "disk space low" {
+		t.Errorf("Message = %q", entry.Message)
+	}
+	if entry.Caller != "bar.go:10" || entry.File != "bar.go" {
+		t.Errorf("Caller/File разобраны неверно: caller=%q file=%q", entry.Caller, entry.File)
+	}
+	if entry.Fields["free"] != 42 {
+		t.Errorf("поле free не распознано как число: %+v", entry.Fields)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	cases := map[string]string{
+		`{"level":"info"}`:        "json",
+		`level=info msg="hi"`:     "logfmt",
+		`INFO plain message here`: "plain",
+	}
+	for line, want := range cases {
+		if got := DetectFormat(line); got != want {
+			t.Errorf("DetectFormat(%q) = %q, хотим %q", line, got, want)
+		}
+	}
+}
+
+func TestAutoParser_DetectsOncePerInstance(t *testing.T) {
+	p := &AutoParser{}
+	entry, err := p.Parse(`{"level":"error","msg":"boom"}`)
+	if err != nil {
+		t.Fatalf("AutoParser.Parse вернул ошибку: %v", err)
+	}
+	if entry.Type != ERROR || entry.Message != "boom" {
+		t.Errorf("неверный разбор после автоопределения: %+v", entry)
+	}
+
+	// Вторая строка в другом формате все равно передается уже определенному
+	// json-парсеру, т.к. формат определяется один раз по первой строке этого
+	// экземпляра - и получает ошибку разбора, а не переопределяет формат.
+	if _, err := p.Parse("level=info msg=ignored-format-switch"); err == nil {
+		t.Error("ожидалась ошибка: второй вызов должен использовать уже определенный json-парсер")
+	}
+}
+
+func TestNewParser_UnknownFormat(t *testing.T) {
+	if _, err := NewParser("xml"); err == nil {
+		t.Error("ожидалась ошибка для неизвестного формата")
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WindowBucket хранит счетчики сообщений, попавшие в один временной интервал.
+type WindowBucket struct {
+	Start  time.Time
+	Counts map[LogMessageType]int
+	Total  int
+}
+
+// WindowAggregator группирует записи в скользящие интервалы фиксированной
+// длины. Хранится не более capacity последних интервалов (кольцевой буфер):
+// при переполнении старейший интервал вытесняется, что ограничивает память
+// на больших файлах вне зависимости от общего временного диапазона лога.
+type WindowAggregator struct {
+	Window   time.Duration
+	Capacity int
+
+	mu      sync.Mutex
+	buckets map[int64]*WindowBucket
+	order   []int64 // ключи интервалов (unix-секунды начала) в порядке появления
+}
+
+// NewWindowAggregator создает агрегатор с заданной длиной интервала window и
+// максимум capacity хранимых интервалов.
+func NewWindowAggregator(window time.Duration, capacity int) *WindowAggregator {
+	return &WindowAggregator{
+		Window:   window,
+		Capacity: capacity,
+		buckets:  make(map[int64]*WindowBucket),
+	}
+}
+
+// Add учитывает entry в интервале, соответствующем ts.
+func (w *WindowAggregator) Add(entry LogEntry, ts time.Time) {
+	key := ts.Truncate(w.Window).Unix()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	bucket, ok := w.buckets[key]
+	if !ok {
+		bucket = &WindowBucket{Start: time.Unix(key, 0).UTC(), Counts: make(map[LogMessageType]int)}
+		w.buckets[key] = bucket
+		w.order = append(w.order, key)
+		if w.Capacity > 0 && len(w.order) > w.Capacity {
+			oldest := w.order[0]
+			w.order = w.order[1:]
+			delete(w.buckets, oldest)
+		}
+	}
+	bucket.Counts[entry.Type]++
+	bucket.Total++
+}
+
+// Buckets возвращает хранимые интервалы, отсортированные по времени начала.
+func (w *WindowAggregator) Buckets() []*WindowBucket {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make([]*WindowBucket, 0, len(w.order))
+	for _, key := range w.order {
+		result = append(result, w.buckets[key])
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result
+}
+
+// templateCount хранит частоту одного нормализованного шаблона сообщения об ошибке.
+type templateCount struct {
+	Template string
+	Count    int
+}
+
+// templateHeap - min-heap по Count, используемый для отбора top-N шаблонов и
+// для вытеснения наименее частых записей при переполнении трекера.
+type templateHeap []templateCount
+
+func (h templateHeap) Len() int           { return len(h) }
+func (h templateHeap) Less(i, j int) bool { return h[i].Count < h[j].Count }
+func (h templateHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *templateHeap) Push(x any)        { *h = append(*h, x.(templateCount)) }
+func (h *templateHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+var (
+	numberPattern = regexp.MustCompile(`\d+`)
+	uuidPattern   = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+)
+
+// normalizeErrorTemplate убирает из сообщения числа и UUID, чтобы сгруппировать
+// однотипные ошибки, отличающиеся только конкретными значениями.
+func normalizeErrorTemplate(message string) string {
+	message = uuidPattern.ReplaceAllString(message, "#UUID#")
+	message = numberPattern.ReplaceAllString(message, "#")
+	return message
+}
+
+// TopErrorsTracker считает частоту нормализованных шаблонов сообщений об
+// ошибках, ограничивая память maxTracked наиболее частыми шаблонами на момент
+// последнего вытеснения.
+type TopErrorsTracker struct {
+	maxTracked int
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewTopErrorsTracker создает трекер, отслеживающий не более maxTracked
+// различных шаблонов одновременно.
+func NewTopErrorsTracker(maxTracked int) *TopErrorsTracker {
+	return &TopErrorsTracker{maxTracked: maxTracked, counts: make(map[string]int)}
+}
+
+// Add учитывает одно сообщение об ошибке.
+func (t *TopErrorsTracker) Add(message string) {
+	template := normalizeErrorTemplate(message)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.counts[template]++
+	if t.maxTracked > 0 && len(t.counts) > t.maxTracked {
+		t.evictLeastFrequentLocked(template)
+	}
+}
+
+// evictLeastFrequentLocked удаляет наименее частый шаблон. При равенстве
+// частот только что добавленный шаблон newest сохраняется, а вытесняется
+// один из ранее отслеживаемых. Вызывающий должен удерживать t.mu.
+func (t *TopErrorsTracker) evictLeastFrequentLocked(newest string) {
+	minTemplate, minCount := newest, t.counts[newest]
+	for template, count := range t.counts {
+		if template != newest && count <= minCount {
+			minTemplate, minCount = template, count
+		}
+	}
+	delete(t.counts, minTemplate)
+}
+
+// TopN возвращает n наиболее частых шаблонов по убыванию частоты.
+func (t *TopErrorsTracker) TopN(n int) []templateCount {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := make(templateHeap, 0, len(t.counts))
+	for template, count := range t.counts {
+		heap.Push(&h, templateCount{Template: template, Count: count})
+		if h.Len() > n {
+			heap.Pop(&h)
+		}
+	}
+
+	result := make([]templateCount, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(&h).(templateCount)
+	}
+	return result
+}
+
+// WindowedReport - сериализуемое представление отчета по интервалам и top
+// ошибкам, используемое для JSON-вывода.
+type WindowedReport struct {
+	Window    string                   `json:"window"`
+	Buckets   []windowedReportBucket   `json:"buckets"`
+	TopErrors []windowedReportTopError `json:"top_errors,omitempty"`
+}
+
+type windowedReportBucket struct {
+	Start  time.Time              `json:"start"`
+	Counts map[LogMessageType]int `json:"counts"`
+	Total  int                    `json:"total"`
+}
+
+type windowedReportTopError struct {
+	Template string `json:"template"`
+	Count    int    `json:"count"`
+}
+
+// BuildWindowedReport собирает WindowedReport из агрегатора и (опционально)
+// трекера ошибок. agg может быть nil, если включен только отчет top-errors.
+func BuildWindowedReport(agg *WindowAggregator, topErrors *TopErrorsTracker, topN int) WindowedReport {
+	var report WindowedReport
+	if agg != nil {
+		report.Window = agg.Window.String()
+		for _, bucket := range agg.Buckets() {
+			report.Buckets = append(report.Buckets, windowedReportBucket{
+				Start:  bucket.Start,
+				Counts: bucket.Counts,
+				Total:  bucket.Total,
+			})
+		}
+	}
+	if topErrors != nil {
+		for _, tc := range topErrors.TopN(topN) {
+			report.TopErrors = append(report.TopErrors, windowedReportTopError{Template: tc.Template, Count: tc.Count})
+		}
+	}
+	return report
+}
+
+// PrintWindowedReportText выводит отчет в виде текстовой таблицы.
+func PrintWindowedReportText(w io.Writer, report WindowedReport) error {
+	fmt.Fprintf(w, "Окно: %s\n", report.Window)
+	fmt.Fprintln(w, "Начало интервала\t\tERROR\tWARNING\tINFO\tВсего")
+	for _, bucket := range report.Buckets {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n",
+			bucket.Start.Format(time.RFC3339),
+			bucket.Counts[ERROR], bucket.Counts[WARNING], bucket.Counts[INFO], bucket.Total)
+	}
+	if len(report.TopErrors) > 0 {
+		fmt.Fprintln(w, "\nТоп ошибок:")
+		for i, te := range report.TopErrors {
+			fmt.Fprintf(w, "%d. (%d) %s\n", i+1, te.Count, te.Template)
+		}
+	}
+	return nil
+}
+
+// PrintWindowedReportJSON выводит отчет в формате JSON.
+func PrintWindowedReportJSON(w io.Writer, report WindowedReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
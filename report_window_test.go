@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWindowAggregator_AddAndBuckets(t *testing.T) {
+	agg := NewWindowAggregator(time.Minute, 10)
+	base := time.Date(2026, 1, 1, 12, 0, 30, 0, time.UTC)
+
+	agg.Add(LogEntry{Type: ERROR}, base)
+	agg.Add(LogEntry{Type: INFO}, base.Add(10*time.Second))
+	agg.Add(LogEntry{Type: INFO}, base.Add(time.Minute))
+
+	buckets := agg.Buckets()
+	if len(buckets) != 2 {
+		t.Fatalf("ожидалось 2 интервала, получено %d", len(buckets))
+	}
+	if buckets[0].Total != 2 || buckets[0].Counts[ERROR] != 1 || buckets[0].Counts[INFO] != 1 {
+		t.Errorf("первый интервал собран неверно: %+v", buckets[0])
+	}
+	if buckets[1].Total != 1 || buckets[1].Counts[INFO] != 1 {
+		t.Errorf("второй интервал собран неверно: %+v", buckets[1])
+	}
+	if !buckets[0].Start.Before(buckets[1].Start) {
+		t.Errorf("интервалы должны быть отсортированы по времени начала")
+	}
+}
+
+func TestWindowAggregator_CapacityEviction(t *testing.T) {
+	agg := NewWindowAggregator(time.Second, 2)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	agg.Add(LogEntry{Type: INFO}, base)
+	agg.Add(LogEntry{Type: INFO}, base.Add(time.Second))
+	agg.Add(LogEntry{Type: INFO}, base.Add(2*time.Second))
+
+	buckets := agg.Buckets()
+	if len(buckets) != 2 {
+		t.Fatalf("ожидалось 2 интервала после вытеснения, получено %d", len(buckets))
+	}
+	if !buckets[0].Start.Equal(base.Add(time.Second)) {
+		t.Errorf("самый старый интервал должен был быть вытеснен, получили %+v", buckets[0].Start)
+	}
+}
+
+func TestNormalizeErrorTemplate(t *testing.T) {
+	cases := map[string]string{
+		"connection to 10.0.0.1 failed after 3 retries":       "connection to #.#.#.# failed after # retries",
+		"user 123e4567-e89b-12d3-a456-426614174000 not found": "user #UUID# not found",
+		"timeout waiting for 42ms":                            "timeout waiting for #ms",
+	}
+	for in, want := range cases {
+		if got := normalizeErrorTemplate(in); got != want {
+			t.Errorf("normalizeErrorTemplate(%q) = %q, хотим %q", in, got, want)
+		}
+	}
+}
+
+func TestTopErrorsTracker_TopN(t *testing.T) {
+	tr := NewTopErrorsTracker(0)
+	for i := 0; i < 5; i++ {
+		tr.Add("disk full on node 1")
+	}
+	for i := 0; i < 2; i++ {
+		tr.Add("disk full on node 2")
+	}
+	tr.Add("connection refused")
+
+	top := tr.TopN(2)
+	if len(top) != 2 {
+		t.Fatalf("ожидалось 2 шаблона, получено %d", len(top))
+	}
+	if top[0].Template != "disk full on node #" || top[0].Count != 7 {
+		t.Errorf("первый шаблон ожидался 'disk full on node #' с count 7, получили %+v", top[0])
+	}
+	if top[1].Count != 1 {
+		t.Errorf("второй шаблон должен иметь count 1, получили %+v", top[1])
+	}
+}
+
+func TestTopErrorsTracker_EvictsLeastFrequentWhenOverCapacity(t *testing.T) {
+	tr := NewTopErrorsTracker(2)
+	tr.Add("error A")
+	tr.Add("error A")
+	tr.Add("error B")
+	// Добавление третьего уникального шаблона при maxTracked=2 должно
+	// вытеснить наименее частый из уже отслеживаемых ("error B", count 1).
+	tr.Add("error C")
+	tr.Add("error C")
+
+	top := tr.TopN(10)
+	templates := make(map[string]int)
+	for _, tc := range top {
+		templates[tc.Template] = tc.Count
+	}
+	if _, ok := templates["error B"]; ok {
+		t.Errorf("ожидалось, что 'error B' будет вытеснен как наименее частый, получили %+v", templates)
+	}
+	if templates["error A"] != 2 || templates["error C"] != 2 {
+		t.Errorf("неверные счетчики после вытеснения: %+v", templates)
+	}
+}
+
+func TestBuildWindowedReport(t *testing.T) {
+	agg := NewWindowAggregator(time.Minute, 10)
+	agg.Add(LogEntry{Type: ERROR}, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tr := NewTopErrorsTracker(0)
+	tr.Add("boom 1")
+	tr.Add("boom 2")
+
+	report := BuildWindowedReport(agg, tr, 5)
+	if report.Window != "1m0s" {
+		t.Errorf("Window = %q", report.Window)
+	}
+	if len(report.Buckets) != 1 || report.Buckets[0].Total != 1 {
+		t.Errorf("неверные интервалы в отчете: %+v", report.Buckets)
+	}
+	if len(report.TopErrors) != 1 || report.TopErrors[0].Count != 2 {
+		t.Errorf("неверный top-errors в отчете: %+v", report.TopErrors)
+	}
+}
+
+func TestPrintWindowedReportJSON(t *testing.T) {
+	report := BuildWindowedReport(nil, nil, 0)
+	report.Window = "1m0s"
+
+	var buf bytes.Buffer
+	if err := PrintWindowedReportJSON(&buf, report); err != nil {
+		t.Fatalf("PrintWindowedReportJSON вернул ошибку: %v", err)
+	}
+
+	var decoded WindowedReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("результат не является валидным JSON: %v", err)
+	}
+	if decoded.Window != "1m0s" {
+		t.Errorf("Window после декодирования = %q", decoded.Window)
+	}
+}
+
+func TestPrintWindowedReportText(t *testing.T) {
+	report := BuildWindowedReport(nil, nil, 0)
+	report.Window = "1m0s"
+	report.TopErrors = []windowedReportTopError{{Template: "boom #", Count: 3}}
+
+	var buf bytes.Buffer
+	if err := PrintWindowedReportText(&buf, report); err != nil {
+		t.Fatalf("PrintWindowedReportText вернул ошибку: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("Топ ошибок:")) {
+		t.Errorf("текстовый вывод должен содержать раздел top-errors: %s", buf.String())
+	}
+}
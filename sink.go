@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// Sink представляет получателя для отчётов и перенаправленных записей лога.
+type Sink interface {
+	Write(entry LogEntry) error
+	Flush() error
+	Close() error
+}
+
+// ConsoleSink пишет записи в консоль (stdout/stderr).
+type ConsoleSink struct {
+	MinLevel LogMessageType
+	out      io.Writer
+	mutex    sync.Mutex
+}
+
+// NewConsoleSink создает синк, пишущий в os.Stdout.
+func NewConsoleSink(minLevel LogMessageType) *ConsoleSink {
+	return &ConsoleSink{MinLevel: minLevel, out: os.Stdout}
+}
+
+func (s *ConsoleSink) Write(entry LogEntry) error {
+	if !levelAtLeast(entry.Type, s.MinLevel) {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err := fmt.Fprintf(s.out, "%s %s\n", entry.Type, entry.Message)
+	return err
+}
+
+func (s *ConsoleSink) Flush() error { return nil }
+func (s *ConsoleSink) Close() error { return nil }
+
+// DiscardSink отбрасывает все записи; полезен в тестах и бенчмарках, где
+// нужен рабочий Sink без побочных эффектов ввода-вывода.
+type DiscardSink struct {
+	MinLevel LogMessageType
+}
+
+// NewDiscardSink создает синк, отбрасывающий все записи.
+func NewDiscardSink(minLevel LogMessageType) *DiscardSink {
+	return &DiscardSink{MinLevel: minLevel}
+}
+
+func (s *DiscardSink) Write(entry LogEntry) error { return nil }
+func (s *DiscardSink) Flush() error               { return nil }
+func (s *DiscardSink) Close() error               { return nil }
+
+// FileSink пишет записи в файл на диске, ротируя его по достижении MaxBytes.
+type FileSink struct {
+	Path     string
+	MinLevel LogMessageType
+	MaxBytes int64
+
+	mutex   sync.Mutex
+	file    *os.File
+	written int64
+}
+
+// NewFileSink открывает (или создает) файл по пути path для дозаписи.
+func NewFileSink(path string, minLevel LogMessageType, maxBytes int64) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при открытии файла синка: %v", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("ошибка при получении информации о файле синка: %v", err)
+	}
+	return &FileSink{Path: path, MinLevel: minLevel, MaxBytes: maxBytes, file: f, written: info.Size()}, nil
+}
+
+func (s *FileSink) Write(entry LogEntry) error {
+	if !levelAtLeast(entry.Type, s.MinLevel) {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.MaxBytes > 0 && s.written >= s.MaxBytes {
+		if err := s.rotateLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := fmt.Fprintf(s.file, "%s %s\n", entry.Type, entry.Message)
+	s.written += int64(n)
+	return err
+}
+
+// rotateLocked переименовывает текущий файл с суффиксом .1 и открывает новый.
+// Вызывающий должен удерживать s.mutex.
+func (s *FileSink) rotateLocked() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("ошибка при закрытии файла перед ротацией: %v", err)
+	}
+	if err := os.Rename(s.Path, s.Path+".1"); err != nil {
+		return fmt.Errorf("ошибка при ротации файла синка: %v", err)
+	}
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка при создании файла после ротации: %v", err)
+	}
+	s.file = f
+	s.written = 0
+	return nil
+}
+
+func (s *FileSink) Flush() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.file.Close()
+}
+
+// SyslogSink отправляет записи по UDP в духе простого syslog-приемника.
+type SyslogSink struct {
+	MinLevel LogMessageType
+	conn     net.Conn
+}
+
+// NewSyslogSink устанавливает UDP-соединение с addr (host:port).
+func NewSyslogSink(addr string, minLevel LogMessageType) (*SyslogSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при подключении к syslog-приемнику: %v", err)
+	}
+	return &SyslogSink{MinLevel: minLevel, conn: conn}, nil
+}
+
+func (s *SyslogSink) Write(entry LogEntry) error {
+	if !levelAtLeast(entry.Type, s.MinLevel) {
+		return nil
+	}
+	_, err := fmt.Fprintf(s.conn, "%s %s\n", entry.Type, entry.Message)
+	return err
+}
+
+func (s *SyslogSink) Flush() error { return nil }
+func (s *SyslogSink) Close() error { return s.conn.Close() }
+
+// KafkaSink публикует записи лога в Kafka-топик через github.com/segmentio/kafka-go.
+type KafkaSink struct {
+	MinLevel LogMessageType
+	writer   *kafka.Writer
+}
+
+// NewKafkaSink создает синк, пишущий в топик topic на брокерах brokers.
+func NewKafkaSink(brokers []string, topic string, minLevel LogMessageType) *KafkaSink {
+	return &KafkaSink{
+		MinLevel: minLevel,
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Topic:    topic,
+			Balancer: &kafka.LeastBytes{},
+		},
+	}
+}
+
+func (s *KafkaSink) Write(entry LogEntry) error {
+	if !levelAtLeast(entry.Type, s.MinLevel) {
+		return nil
+	}
+	msg := kafka.Message{
+		Key:   []byte(entry.Type),
+		Value: []byte(entry.Message),
+	}
+	if err := s.writer.WriteMessages(context.Background(), msg); err != nil {
+		return fmt.Errorf("ошибка при отправке записи в kafka: %v", err)
+	}
+	return nil
+}
+
+func (s *KafkaSink) Flush() error { return nil }
+func (s *KafkaSink) Close() error { return s.writer.Close() }
+
+// MultiSink рассылает каждую запись во все вложенные синки (fan-out).
+// Ошибка любого из синков не прерывает запись в остальные; возвращается
+// первая встреченная ошибка, последующие отбрасываются.
+type MultiSink struct {
+	Sinks []Sink
+}
+
+// NewMultiSink оборачивает набор синков в один.
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{Sinks: sinks}
+}
+
+func (m *MultiSink) Write(entry LogEntry) error {
+	var firstErr error
+	for _, sink := range m.Sinks {
+		if err := sink.Write(entry); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m *MultiSink) Flush() error {
+	var firstErr error
+	for _, sink := range m.Sinks {
+		if err := sink.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m *MultiSink) Close() error {
+	var firstErr error
+	for _, sink := range m.Sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// levelAtLeast сообщает, соответствует ли level минимальному порогу min.
+func levelAtLeast(level, min LogMessageType) bool {
+	return severityRank(level) >= severityRank(min)
+}
+
+// severityRank возвращает числовой ранг типа сообщения для сравнения уровней.
+func severityRank(t LogMessageType) int {
+	switch t {
+	case INFO:
+		return 0
+	case WARNING:
+		return 1
+	case ERROR:
+		return 2
+	default:
+		return 0
+	}
+}
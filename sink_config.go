@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SinkConfig описывает один синк в конфигурационном файле.
+type SinkConfig struct {
+	Type     string   `yaml:"type"` // console, file, syslog, kafka
+	MinLevel string   `yaml:"min_level"`
+	Path     string   `yaml:"path,omitempty"`      // для file
+	MaxBytes int64    `yaml:"max_bytes,omitempty"` // для file, 0 = без ротации
+	Addr     string   `yaml:"addr,omitempty"`      // для syslog
+	Brokers  []string `yaml:"brokers,omitempty"`   // для kafka
+	Topic    string   `yaml:"topic,omitempty"`     // для kafka
+}
+
+// SinksConfig представляет секцию sinks конфигурационного файла анализатора.
+type SinksConfig struct {
+	Sinks []SinkConfig `yaml:"sinks"`
+}
+
+// LoadSinksConfig читает и разбирает YAML-файл конфигурации синков.
+func LoadSinksConfig(path string) (*SinksConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при чтении конфигурации синков: %v", err)
+	}
+	var cfg SinksConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("ошибка при разборе конфигурации синков: %v", err)
+	}
+	return &cfg, nil
+}
+
+// BuildSink создает синк по его описанию в конфигурации.
+func BuildSink(c SinkConfig) (Sink, error) {
+	minLevel := LogMessageType(strings.ToUpper(c.MinLevel))
+	if minLevel == "" {
+		minLevel = INFO
+	}
+
+	switch c.Type {
+	case "console":
+		return NewConsoleSink(minLevel), nil
+	case "file":
+		return NewFileSink(c.Path, minLevel, c.MaxBytes)
+	case "syslog":
+		return NewSyslogSink(c.Addr, minLevel)
+	case "kafka":
+		return NewKafkaSink(c.Brokers, c.Topic, minLevel), nil
+	default:
+		return nil, fmt.Errorf("неизвестный тип синка: %s", c.Type)
+	}
+}
+
+// BuildMultiSink собирает все синки из конфигурации в один MultiSink.
+func BuildMultiSink(cfg *SinksConfig) (*MultiSink, error) {
+	sinks := make([]Sink, 0, len(cfg.Sinks))
+	for _, sc := range cfg.Sinks {
+		sink, err := BuildSink(sc)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return NewMultiSink(sinks...), nil
+}
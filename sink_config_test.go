@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildSink_UppercasesMinLevel(t *testing.T) {
+	s, err := BuildSink(SinkConfig{Type: "console", MinLevel: "warning"})
+	if err != nil {
+		t.Fatalf("BuildSink вернул ошибку: %v", err)
+	}
+	cs, ok := s.(*ConsoleSink)
+	if !ok {
+		t.Fatalf("BuildSink(console) вернул %T, хотим *ConsoleSink", s)
+	}
+	if cs.MinLevel != WARNING {
+		t.Errorf("MinLevel = %q, хотим %q (ожидалось приведение к верхнему регистру)", cs.MinLevel, WARNING)
+	}
+}
+
+func TestBuildSink_DefaultsMinLevelToInfo(t *testing.T) {
+	s, err := BuildSink(SinkConfig{Type: "console"})
+	if err != nil {
+		t.Fatalf("BuildSink вернул ошибку: %v", err)
+	}
+	cs := s.(*ConsoleSink)
+	if cs.MinLevel != INFO {
+		t.Errorf("MinLevel = %q, хотим INFO по умолчанию", cs.MinLevel)
+	}
+}
+
+func TestBuildSink_UnknownType(t *testing.T) {
+	if _, err := BuildSink(SinkConfig{Type: "carrier-pigeon"}); err == nil {
+		t.Error("ожидалась ошибка для неизвестного типа синка")
+	}
+}
+
+func TestBuildSink_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+	s, err := BuildSink(SinkConfig{Type: "file", Path: path, MinLevel: "error"})
+	if err != nil {
+		t.Fatalf("BuildSink вернул ошибку: %v", err)
+	}
+	defer s.Close()
+	if _, ok := s.(*FileSink); !ok {
+		t.Fatalf("BuildSink(file) вернул %T, хотим *FileSink", s)
+	}
+}
+
+func TestLoadSinksConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sinks.yaml")
+	yamlContent := "sinks:\n  - type: console\n    min_level: warning\n  - type: file\n    min_level: error\n    path: /tmp/out.log\n"
+	if err := os.WriteFile(path, []byte(yamlContent), 0o644); err != nil {
+		t.Fatalf("ошибка при создании файла конфигурации: %v", err)
+	}
+
+	cfg, err := LoadSinksConfig(path)
+	if err != nil {
+		t.Fatalf("LoadSinksConfig вернул ошибку: %v", err)
+	}
+	if len(cfg.Sinks) != 2 {
+		t.Fatalf("ожидалось 2 синка, получено %d", len(cfg.Sinks))
+	}
+	if cfg.Sinks[0].Type != "console" || cfg.Sinks[0].MinLevel != "warning" {
+		t.Errorf("первый синк разобран неверно: %+v", cfg.Sinks[0])
+	}
+	if cfg.Sinks[1].Type != "file" || cfg.Sinks[1].Path != "/tmp/out.log" {
+		t.Errorf("второй синк разобран неверно: %+v", cfg.Sinks[1])
+	}
+}
+
+func TestLoadSinksConfig_MissingFile(t *testing.T) {
+	if _, err := LoadSinksConfig(filepath.Join(t.TempDir(), "does-not-exist.yaml")); err == nil {
+		t.Error("ожидалась ошибка при отсутствующем файле конфигурации")
+	}
+}
+
+func TestBuildMultiSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+	cfg := &SinksConfig{
+		Sinks: []SinkConfig{
+			{Type: "console", MinLevel: "info"},
+			{Type: "file", MinLevel: "error", Path: path},
+		},
+	}
+	ms, err := BuildMultiSink(cfg)
+	if err != nil {
+		t.Fatalf("BuildMultiSink вернул ошибку: %v", err)
+	}
+	defer ms.Close()
+	if len(ms.Sinks) != 2 {
+		t.Fatalf("ожидалось 2 вложенных синка, получено %d", len(ms.Sinks))
+	}
+
+	if err := ms.Write(LogEntry{Type: ERROR, Message: "boom"}); err != nil {
+		t.Fatalf("Write вернул ошибку: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка при чтении файла синка: %v", err)
+	}
+	if string(data) != "ERROR boom\n" {
+		t.Errorf("файловый синк с min_level=error должен был получить запись, содержимое = %q", string(data))
+	}
+}
+
+func TestBuildMultiSink_PropagatesSinkError(t *testing.T) {
+	cfg := &SinksConfig{Sinks: []SinkConfig{{Type: "unknown-type"}}}
+	if _, err := BuildMultiSink(cfg); err == nil {
+		t.Error("ожидалась ошибка при неизвестном типе синка в конфигурации")
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConsoleSink_FiltersByMinLevel(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewConsoleSink(WARNING)
+	s.out = &buf
+
+	if err := s.Write(LogEntry{Type: INFO, Message: "ignored"}); err != nil {
+		t.Fatalf("Write вернул ошибку: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("INFO не должен проходить при MinLevel=WARNING, получили %q", buf.String())
+	}
+
+	if err := s.Write(LogEntry{Type: ERROR, Message: "boom"}); err != nil {
+		t.Fatalf("Write вернул ошибку: %v", err)
+	}
+	if buf.String() != "ERROR boom\n" {
+		t.Errorf("buf = %q", buf.String())
+	}
+}
+
+func TestFileSink_WritesAndAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+	s, err := NewFileSink(path, INFO, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink вернул ошибку: %v", err)
+	}
+	if err := s.Write(LogEntry{Type: INFO, Message: "first"}); err != nil {
+		t.Fatalf("Write вернул ошибку: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close вернул ошибку: %v", err)
+	}
+
+	s2, err := NewFileSink(path, INFO, 0)
+	if err != nil {
+		t.Fatalf("повторный NewFileSink вернул ошибку: %v", err)
+	}
+	defer s2.Close()
+	if err := s2.Write(LogEntry{Type: INFO, Message: "second"}); err != nil {
+		t.Fatalf("Write вернул ошибку: %v", err)
+	}
+	if err := s2.Flush(); err != nil {
+		t.Fatalf("Flush вернул ошибку: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка при чтении файла синка: %v", err)
+	}
+	want := "INFO first\nINFO second\n"
+	if string(data) != want {
+		t.Errorf("содержимое файла = %q, хотим %q (NewFileSink должен дозаписывать, а не перезаписывать)", string(data), want)
+	}
+}
+
+func TestFileSink_RotatesOnMaxBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+	s, err := NewFileSink(path, INFO, 11)
+	if err != nil {
+		t.Fatalf("NewFileSink вернул ошибку: %v", err)
+	}
+	defer s.Close()
+
+	// "INFO first\n" - ровно 11 байт, достигает MaxBytes=11, но ротация
+	// проверяется перед записью, так что после первой записи ее еще нет.
+	if err := s.Write(LogEntry{Type: INFO, Message: "first"}); err != nil {
+		t.Fatalf("Write вернул ошибку: %v", err)
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("ротация не должна была произойти после первой записи")
+	}
+
+	// Следующая запись находит s.written >= MaxBytes и ротирует перед записью.
+	if err := s.Write(LogEntry{Type: INFO, Message: "second"}); err != nil {
+		t.Fatalf("Write вернул ошибку: %v", err)
+	}
+
+	rotated, err := os.ReadFile(path + ".1")
+	if err != nil {
+		t.Fatalf("ожидался ротированный файл %s.1: %v", path, err)
+	}
+	if string(rotated) != "INFO first\n" {
+		t.Errorf("ротированный файл = %q", string(rotated))
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка при чтении текущего файла синка: %v", err)
+	}
+	if string(current) != "INFO second\n" {
+		t.Errorf("текущий файл после ротации = %q", string(current))
+	}
+}
+
+type fakeSink struct {
+	writeErr error
+	flushErr error
+	closeErr error
+	writes   []LogEntry
+	flushed  bool
+	closed   bool
+}
+
+func (f *fakeSink) Write(entry LogEntry) error {
+	f.writes = append(f.writes, entry)
+	return f.writeErr
+}
+func (f *fakeSink) Flush() error { f.flushed = true; return f.flushErr }
+func (f *fakeSink) Close() error { f.closed = true; return f.closeErr }
+
+func TestMultiSink_FanOutToAllSinks(t *testing.T) {
+	a, b := &fakeSink{}, &fakeSink{}
+	m := NewMultiSink(a, b)
+
+	entry := LogEntry{Type: ERROR, Message: "boom"}
+	if err := m.Write(entry); err != nil {
+		t.Fatalf("Write вернул ошибку: %v", err)
+	}
+	if len(a.writes) != 1 || len(b.writes) != 1 {
+		t.Errorf("запись должна была дойти до обоих синков: a=%v b=%v", a.writes, b.writes)
+	}
+
+	if err := m.Flush(); err != nil {
+		t.Fatalf("Flush вернул ошибку: %v", err)
+	}
+	if !a.flushed || !b.flushed {
+		t.Errorf("Flush должен был дойти до обоих синков")
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close вернул ошибку: %v", err)
+	}
+	if !a.closed || !b.closed {
+		t.Errorf("Close должен был дойти до обоих синков")
+	}
+}
+
+func TestMultiSink_ReturnsFirstErrorButKeepsWritingToOthers(t *testing.T) {
+	failErr := errors.New("первый синк недоступен")
+	a := &fakeSink{writeErr: failErr}
+	b := &fakeSink{}
+	m := NewMultiSink(a, b)
+
+	err := m.Write(LogEntry{Type: ERROR, Message: "boom"})
+	if !errors.Is(err, failErr) {
+		t.Errorf("Write() = %v, хотим первую встреченную ошибку %v", err, failErr)
+	}
+	if len(b.writes) != 1 {
+		t.Errorf("ошибка в одном синке не должна останавливать запись в остальные, b.writes=%v", b.writes)
+	}
+}
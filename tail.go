@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// tailState хранит состояние одного открытого файла между итерациями цикла
+// слежения, включая хвост строки, дозапись которой (завершающий '\n') еще не
+// произошла.
+type tailState struct {
+	file    *os.File
+	reader  *bufio.Reader
+	inode   uint64
+	size    int64
+	pending string
+}
+
+// openTail открывает path для слежения, позиционируясь в конец файла, как tail -F.
+func openTail(path string) (*tailState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при открытии файла для слежения %q: %v", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("ошибка при получении информации о файле %q: %v", path, err)
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("ошибка при позиционировании в конец файла %q: %v", path, err)
+	}
+	return &tailState{file: f, reader: bufio.NewReader(f), inode: inodeOf(info), size: info.Size()}, nil
+}
+
+// reopenFromStart открывает path заново с начала - используется после
+// обнаружения ротации, когда старый файл-дескриптор уже указывает на
+// удаленный/переименованный inode.
+func reopenFromStart(path string) (*tailState, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при переоткрытии файла после ротации %q: %v", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("ошибка при получении информации о файле %q: %v", path, err)
+	}
+	return &tailState{file: f, reader: bufio.NewReader(f), inode: inodeOf(info)}, nil
+}
+
+func inodeOf(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return stat.Ino
+	}
+	return 0
+}
+
+// rotated сообщает, сменился ли файл по пути path относительно state: по
+// inode (переименование/пересоздание) или по усечению на месте (текущий
+// размер на диске меньше уже прочитанного).
+func (s *tailState) rotated(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return true // файл исчез, скорее всего идет ротация - переоткрываем
+	}
+	if inodeOf(info) != s.inode {
+		return true
+	}
+	return info.Size() < s.size
+}
+
+// readAvailableLines читает все полные строки, доступные сейчас в буфере
+// state, и отправляет разобранные записи в out. Данные, дочитанные до EOF без
+// завершающего '\n' (дозапись в процессе), копятся в state.pending и
+// разбираются только после того, как появится остаток строки с переводом
+// строки - иначе одна строка, дописанная по частям, разобралась бы как
+// несколько независимых (и вторая - без префикса типа сообщения).
+func (s *tailState) readAvailableLines(path string, parser Parser, out chan<- fileEntry) error {
+	for {
+		line, err := s.reader.ReadString('\n')
+		s.size += int64(len(line))
+
+		if err == io.EOF {
+			s.pending += line
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка при чтении файла %q: %v", path, err)
+		}
+
+		full := s.pending + line
+		s.pending = ""
+		entry, perr := parser.Parse(full)
+		if perr != nil {
+			return fmt.Errorf("ошибка при разборе строки файла %q: %v", path, perr)
+		}
+		out <- fileEntry{entry: entry, path: path}
+	}
+}
+
+// RunFollow переводит анализатор в режим слежения за единственным путем из
+// la.LogFilePaths (аналог `tail -F`; при другом числе путей возвращается
+// ошибка): держит файл открытым после EOF,
+// дочитывает дозаписанные данные и переоткрывает файл при обнаружении
+// ротации. Изменения файла отслеживаются через fsnotify, когда он доступен,
+// и всегда дополнительно опрашиваются по таймеру - на случай недоступности
+// inotify (например, сетевая файловая система) или отсутствия fsnotify.
+// Каждые la.ReportInterval в Sink выводится инкрементальный отчет, а по
+// отмене ctx (например, по SIGINT) цикл завершается финальной сводкой.
+func (la *LogAnalyzer) RunFollow(ctx context.Context) error {
+	if len(la.LogFilePaths) != 1 {
+		return fmt.Errorf("режим -follow поддерживает ровно один лог-файл, получено %d", len(la.LogFilePaths))
+	}
+	path := la.LogFilePaths[0]
+
+	parser, err := NewParser(la.Format)
+	if err != nil {
+		return err
+	}
+
+	state, err := openTail(path)
+	if err != nil {
+		return err
+	}
+	defer state.file.Close()
+
+	watcher, werr := fsnotify.NewWatcher()
+	if werr == nil {
+		defer watcher.Close()
+		_ = watcher.Add(filepath.Dir(path))
+	}
+
+	entries := make(chan fileEntry, 256)
+	done := make(chan struct{})
+	go la.consumeFollowed(entries, done)
+
+	reportInterval := la.ReportInterval
+	if reportInterval <= 0 {
+		reportInterval = 10 * time.Second
+	}
+	reportTicker := time.NewTicker(reportInterval)
+	defer reportTicker.Stop()
+
+	// pollTicker - запасной опрос на случай, если fsnotify недоступен или
+	// пропустил событие (например, при ротации через переименование).
+	pollTicker := time.NewTicker(time.Second)
+	defer pollTicker.Stop()
+
+	var watchEvents <-chan fsnotify.Event
+	var watchErrs <-chan error
+	if watcher != nil {
+		watchEvents = watcher.Events
+		watchErrs = watcher.Errors
+	}
+
+	lastReported := 0
+	printDelta := func() {
+		total := la.totalMessages()
+		delta := total - lastReported
+		lastReported = total
+		msg := fmt.Sprintf("Инкрементальный отчет: +%d сообщений, всего %d", delta, total)
+		_ = la.Sink.Write(LogEntry{Type: INFO, Message: msg})
+	}
+
+	checkAndRead := func() error {
+		if state.rotated(path) {
+			newState, err := reopenFromStart(path)
+			if err != nil {
+				// Новый файл еще не создан - повторяем при следующем событии или опросе.
+				return nil
+			}
+			state.file.Close()
+			state = newState
+			if watcher != nil {
+				_ = watcher.Add(path)
+			}
+		}
+		return state.readAvailableLines(path, parser, entries)
+	}
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case _, ok := <-watchEvents:
+			if !ok {
+				watchEvents = nil
+				continue
+			}
+			if err := checkAndRead(); err != nil {
+				return err
+			}
+		case err, ok := <-watchErrs:
+			if ok && err != nil {
+				fmt.Printf("Ошибка наблюдателя файловой системы: %v\n", err)
+			}
+		case <-pollTicker.C:
+			if err := checkAndRead(); err != nil {
+				return err
+			}
+		case <-reportTicker.C:
+			printDelta()
+		}
+	}
+
+	close(entries)
+	<-done
+	printDelta()
+	return la.Sink.Flush()
+}
+
+// consumeFollowed считает каждую запись, поступившую в режиме слежения, в
+// TotalMessages, а записи, прошедшие фильтры -level и -v/-vmodule, учитывает в
+// статистике и пишет в Sink анализатора, пока entries не будет закрыт.
+func (la *LogAnalyzer) consumeFollowed(entries <-chan fileEntry, done chan<- struct{}) {
+	defer close(done)
+	for fe := range entries {
+		entry := fe.entry
+		la.addTotalMessages(1)
+		if !levelAtLeast(entry.Type, la.DetailLevel) {
+			continue
+		}
+		if la.Verbosity != nil && !la.Verbosity.Allow(entry) {
+			continue
+		}
+
+		la.updateStats(entry.Type)
+		if la.WindowAgg != nil {
+			ts := entry.Timestamp
+			if ts.IsZero() {
+				ts = time.Now()
+			}
+			la.WindowAgg.Add(entry, ts)
+		}
+		if la.TopErrors != nil && entry.Type == ERROR {
+			la.TopErrors.Add(entry.Message)
+		}
+		_ = la.Sink.Write(entry)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadAvailableLines_BuffersPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("ошибка при создании файла: %v", err)
+	}
+
+	state, err := openTail(path)
+	if err != nil {
+		t.Fatalf("openTail вернул ошибку: %v", err)
+	}
+	defer state.file.Close()
+
+	out := make(chan fileEntry, 10)
+	parser := PlainParser{}
+
+	// Дозаписываем строку без завершающего '\n' - она не должна разобраться
+	// как отдельная запись, а должна осесть в state.pending.
+	appendToFile(t, path, "ERROR partial me")
+	if err := state.readAvailableLines(path, parser, out); err != nil {
+		t.Fatalf("readAvailableLines вернул ошибку: %v", err)
+	}
+	select {
+	case fe := <-out:
+		t.Fatalf("не ожидалась запись до завершения строки, получили %+v", fe)
+	default:
+	}
+	if state.pending != "ERROR partial me" {
+		t.Errorf("pending = %q, хотим %q", state.pending, "ERROR partial me")
+	}
+
+	// Дописываем остаток строки - теперь должна появиться ровно одна запись,
+	// собранная из pending + новых данных.
+	appendToFile(t, path, "ssage\n")
+	if err := state.readAvailableLines(path, parser, out); err != nil {
+		t.Fatalf("readAvailableLines вернул ошибку: %v", err)
+	}
+	select {
+	case fe := <-out:
+		if fe.entry.Message != "partial message\n" {
+			t.Errorf("Message = %q, хотим %q", fe.entry.Message, "partial message\n")
+		}
+	default:
+		t.Fatal("ожидалась одна запись после дозаписи остатка строки")
+	}
+	if state.pending != "" {
+		t.Errorf("pending должен быть пуст после разбора полной строки, получили %q", state.pending)
+	}
+}
+
+func TestReadAvailableLines_MultipleCompleteLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("ошибка при создании файла: %v", err)
+	}
+
+	state, err := openTail(path)
+	if err != nil {
+		t.Fatalf("openTail вернул ошибку: %v", err)
+	}
+	defer state.file.Close()
+
+	out := make(chan fileEntry, 10)
+	appendToFile(t, path, "ERROR one\nINFO two\n")
+	if err := state.readAvailableLines(path, PlainParser{}, out); err != nil {
+		t.Fatalf("readAvailableLines вернул ошибку: %v", err)
+	}
+	close(out)
+
+	var got []fileEntry
+	for fe := range out {
+		got = append(got, fe)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ожидалось 2 записи, получено %d", len(got))
+	}
+	if got[0].entry.Type != ERROR || got[1].entry.Type != INFO {
+		t.Errorf("неверные типы записей: %+v", got)
+	}
+}
+
+func TestTailState_Rotated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("ERROR hello\n"), 0o644); err != nil {
+		t.Fatalf("ошибка при создании файла: %v", err)
+	}
+
+	state, err := openTail(path)
+	if err != nil {
+		t.Fatalf("openTail вернул ошибку: %v", err)
+	}
+	defer state.file.Close()
+
+	if state.rotated(path) {
+		t.Error("файл без изменений не должен считаться ротированным")
+	}
+
+	// Усечение на месте: размер на диске становится меньше, чем уже прочитано.
+	state.size = 1000
+	if !state.rotated(path) {
+		t.Error("усечение файла должно определяться как ротация")
+	}
+	state.size = 0
+
+	// Пересоздание файла по тому же пути (например, logrotate create): inode меняется.
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("ошибка при удалении файла: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("ERROR new file\n"), 0o644); err != nil {
+		t.Fatalf("ошибка при пересоздании файла: %v", err)
+	}
+	if !state.rotated(path) {
+		t.Error("пересоздание файла с новым inode должно определяться как ротация")
+	}
+}
+
+func TestTailState_Rotated_FileMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("ошибка при создании файла: %v", err)
+	}
+	state, err := openTail(path)
+	if err != nil {
+		t.Fatalf("openTail вернул ошибку: %v", err)
+	}
+	defer state.file.Close()
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("ошибка при удалении файла: %v", err)
+	}
+	if !state.rotated(path) {
+		t.Error("исчезновение файла должно определяться как ротация")
+	}
+}
+
+func TestOpenTail_StartsAtEnd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("ERROR already here\n"), 0o644); err != nil {
+		t.Fatalf("ошибка при создании файла: %v", err)
+	}
+
+	state, err := openTail(path)
+	if err != nil {
+		t.Fatalf("openTail вернул ошибку: %v", err)
+	}
+	defer state.file.Close()
+
+	out := make(chan fileEntry, 10)
+	if err := state.readAvailableLines(path, PlainParser{}, out); err != nil {
+		t.Fatalf("readAvailableLines вернул ошибку: %v", err)
+	}
+	select {
+	case fe := <-out:
+		t.Fatalf("openTail должен был начать чтение с конца файла, но получили запись %+v", fe)
+	default:
+	}
+}
+
+func appendToFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("ошибка при открытии файла для дозаписи: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatalf("ошибка при дозаписи в файл: %v", err)
+	}
+}
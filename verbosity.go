@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// vmoduleRule описывает одно правило из спецификации -vmodule: шаблон
+// имени файла (допускает glob, как в path.Match) и максимальный уровень
+// verbosity, при котором записи из совпавших файлов еще пропускаются.
+type vmoduleRule struct {
+	pattern string
+	level   int
+}
+
+// VerbosityFilter решает, какие записи пропускать на основе глобальной
+// verbosity (-v) и per-module порогов (-vmodule), заданных в стиле glog/klog.
+type VerbosityFilter struct {
+	V     int
+	rules []vmoduleRule
+	cache sync.Map // string(file) -> int(level), V если правило не найдено
+}
+
+// NewVerbosityFilter разбирает спецификацию vmodule (например
+// "server/*=2,db.go=4") и строит фильтр с глобальным уровнем v.
+func NewVerbosityFilter(v int, vmoduleSpec string) (*VerbosityFilter, error) {
+	rules, err := parseVModule(vmoduleSpec)
+	if err != nil {
+		return nil, err
+	}
+	return &VerbosityFilter{V: v, rules: rules}, nil
+}
+
+// parseVModule разбирает строку вида "server/*=2,db.go=4" в список правил.
+func parseVModule(spec string) ([]vmoduleRule, error) {
+	var rules []vmoduleRule
+	if spec == "" {
+		return rules, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("некорректное правило vmodule: %q", part)
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("некорректный уровень в правиле vmodule %q: %v", part, err)
+		}
+		rules = append(rules, vmoduleRule{pattern: strings.TrimSpace(kv[0]), level: level})
+	}
+	return rules, nil
+}
+
+// thresholdFor возвращает максимальный допустимый уровень verbosity для файла file: если файл
+// совпадает с одним из правил vmodule, используется его уровень, иначе V.
+// Результат кэшируется в sync.Map, так как совпавший файл повторяется на
+// множестве строк одного лог-файла.
+func (f *VerbosityFilter) thresholdFor(file string) int {
+	if file == "" || len(f.rules) == 0 {
+		return f.V
+	}
+	if cached, ok := f.cache.Load(file); ok {
+		return cached.(int)
+	}
+
+	level := f.V
+	for _, rule := range f.rules {
+		if matched, _ := path.Match(rule.pattern, file); matched {
+			level = rule.level
+			break
+		}
+	}
+	f.cache.Store(file, level)
+	return level
+}
+
+// Allow сообщает, должна ли запись entry (с заданной verbosity level и
+// исходным файлом file) быть включена в анализ.
+func (f *VerbosityFilter) Allow(entry LogEntry) bool {
+	return entry.Verbosity <= f.thresholdFor(entry.File)
+}
+
+// parseVerbosityAndFile извлекает числовую verbosity (например, из
+// "INFO(3) pkg/foo.go:42 message") и имя исходного файла (без номера строки)
+// из префикса сообщения. Если префикс не соответствует формату, verbosity
+// считается равной 0, а file - пустой строкой.
+func parseVerbosityAndFile(message string) (verbosity int, file string, rest string) {
+	rest = message
+	if len(message) == 0 || message[0] != '(' {
+		return 0, "", message
+	}
+	closeIdx := strings.IndexByte(message, ')')
+	if closeIdx < 0 {
+		return 0, "", message
+	}
+	v, err := strconv.Atoi(message[1:closeIdx])
+	if err != nil {
+		return 0, "", message
+	}
+
+	remainder := strings.TrimPrefix(message[closeIdx+1:], " ")
+	fields := strings.SplitN(remainder, " ", 2)
+	fileField := fields[0]
+	if idx := strings.LastIndexByte(fileField, ':'); idx >= 0 {
+		fileField = fileField[:idx]
+	}
+
+	rest = remainder
+	if len(fields) == 2 {
+		rest = fields[1]
+	}
+	return v, fileField, rest
+}
@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+func TestParseVModule(t *testing.T) {
+	rules, err := parseVModule("server/*=2,db.go=4")
+	if err != nil {
+		t.Fatalf("parseVModule вернул ошибку: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("ожидалось 2 правила, получено %d", len(rules))
+	}
+	if rules[0].pattern != "server/*" || rules[0].level != 2 {
+		t.Errorf("неверное первое правило: %+v", rules[0])
+	}
+	if rules[1].pattern != "db.go" || rules[1].level != 4 {
+		t.Errorf("неверное второе правило: %+v", rules[1])
+	}
+}
+
+func TestParseVModule_Invalid(t *testing.T) {
+	if _, err := parseVModule("server/*"); err == nil {
+		t.Error("ожидалась ошибка при отсутствии '=' в правиле")
+	}
+	if _, err := parseVModule("server/*=abc"); err == nil {
+		t.Error("ожидалась ошибка при нечисловом уровне")
+	}
+}
+
+func TestVerbosityFilter_ThresholdFor(t *testing.T) {
+	f, err := NewVerbosityFilter(1, "server/*=2,db.go=4")
+	if err != nil {
+		t.Fatalf("NewVerbosityFilter вернул ошибку: %v", err)
+	}
+
+	cases := []struct {
+		file string
+		want int
+	}{
+		{"server/main.go", 2},
+		{"db.go", 4},
+		{"other.go", 1}, // нет совпадения - используется глобальный V
+		{"", 1},         // нет файла - используется глобальный V
+	}
+	for _, c := range cases {
+		if got := f.thresholdFor(c.file); got != c.want {
+			t.Errorf("thresholdFor(%q) = %d, хотим %d", c.file, got, c.want)
+		}
+	}
+}
+
+func TestVerbosityFilter_Allow(t *testing.T) {
+	f, err := NewVerbosityFilter(0, "server/*=3")
+	if err != nil {
+		t.Fatalf("NewVerbosityFilter вернул ошибку: %v", err)
+	}
+
+	allowed := LogEntry{Verbosity: 3, File: "server/main.go"}
+	if !f.Allow(allowed) {
+		t.Errorf("запись с verbosity 3 из server/* должна проходить при пороге 3")
+	}
+	blocked := LogEntry{Verbosity: 5, File: "server/main.go"}
+	if f.Allow(blocked) {
+		t.Errorf("запись с verbosity 5 из server/* не должна проходить при пороге 3")
+	}
+	blockedDefault := LogEntry{Verbosity: 1, File: "other.go"}
+	if f.Allow(blockedDefault) {
+		t.Errorf("запись без совпавшего правила должна использовать глобальный V=0")
+	}
+}
+
+func TestParseVerbosityAndFile(t *testing.T) {
+	v, file, rest := parseVerbosityAndFile("(3) pkg/foo.go:42 message")
+	if v != 3 || file != "pkg/foo.go" || rest != "message" {
+		t.Errorf("parseVerbosityAndFile = (%d, %q, %q), хотим (3, \"pkg/foo.go\", \"message\")", v, file, rest)
+	}
+
+	v, file, rest = parseVerbosityAndFile("plain message")
+	if v != 0 || file != "" || rest != "plain message" {
+		t.Errorf("parseVerbosityAndFile для простого сообщения вернул (%d, %q, %q)", v, file, rest)
+	}
+}